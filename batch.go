@@ -0,0 +1,54 @@
+package bolt
+
+import (
+  "github.com/kwf2030/commons/base"
+  "go.etcd.io/bbolt"
+)
+
+// db.Batch may retry fun against a fresh *bbolt.Tx, but that's safe
+// here since PutTx/Delete are idempotent.
+func BatchPut(db *bbolt.DB, bucket []byte, kv map[string][]byte) error {
+  if db == nil || len(bucket) == 0 || len(kv) == 0 {
+    return base.ErrInvalidArgument
+  }
+  return db.Batch(func(tx *bbolt.Tx) error {
+    for k, v := range kv {
+      if e := PutTx(tx, bucket, []byte(k), v); e != nil {
+        return e
+      }
+    }
+    return nil
+  })
+}
+
+func BatchPutKV(db *bbolt.DB, bucket []byte, keys, values [][]byte) error {
+  if db == nil || len(bucket) == 0 || len(keys) == 0 || len(keys) != len(values) {
+    return base.ErrInvalidArgument
+  }
+  return db.Batch(func(tx *bbolt.Tx) error {
+    for i, k := range keys {
+      if e := PutTx(tx, bucket, k, values[i]); e != nil {
+        return e
+      }
+    }
+    return nil
+  })
+}
+
+func BatchDelete(db *bbolt.DB, bucket []byte, keys [][]byte) error {
+  if db == nil || len(bucket) == 0 || len(keys) == 0 {
+    return base.ErrInvalidArgument
+  }
+  return db.Batch(func(tx *bbolt.Tx) error {
+    b := tx.Bucket(bucket)
+    if b == nil {
+      return ErrBucketNotFound
+    }
+    for _, k := range keys {
+      if e := b.Delete(k); e != nil {
+        return e
+      }
+    }
+    return nil
+  })
+}
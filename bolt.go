@@ -14,6 +14,58 @@ var (
   ErrKeyNotFound    = errors.New("key not found")
 )
 
+// bucketPut, bucketEach, bucketEachPrefix, bucketCount, and
+// bucketCountPrefix operate on an already-resolved *bbolt.Bucket, so
+// PutTx/EachKVTx/... (bucket looked up from a *bbolt.Tx) and
+// PutPath/EachKVPath/... (bucket looked up from a Path) share one
+// implementation instead of drifting apart.
+
+func bucketPut(b *bbolt.Bucket, key, value []byte) error {
+  if value == nil {
+    value = []byte{}
+  }
+  return b.Put(key, value)
+}
+
+func bucketEach(b *bbolt.Bucket, fun func([]byte, []byte) error) error {
+  c := b.Cursor()
+  for k, v := c.First(); k != nil; k, v = c.Next() {
+    if e := fun(k, v); e != nil {
+      return e
+    }
+  }
+  return nil
+}
+
+func bucketEachPrefix(b *bbolt.Bucket, prefix []byte, fun func([]byte, []byte) error) error {
+  c := b.Cursor()
+  for k, v := c.Seek(prefix); k != nil; k, v = c.Next() {
+    if !bytes.HasPrefix(k, prefix) {
+      break
+    }
+    if e := fun(k, v); e != nil {
+      return e
+    }
+  }
+  return nil
+}
+
+func bucketCount(b *bbolt.Bucket) int {
+  return b.Stats().KeyN
+}
+
+func bucketCountPrefix(b *bbolt.Bucket, prefix []byte) int {
+  ret := 0
+  c := b.Cursor()
+  for k, _ := c.Seek(prefix); k != nil; k, _ = c.Next() {
+    if !bytes.HasPrefix(k, prefix) {
+      break
+    }
+    ret++
+  }
+  return ret
+}
+
 func Open(path string, buckets ...[]byte) (*bbolt.DB, error) {
   if path == "" {
     return nil, base.ErrInvalidArgument
@@ -41,40 +93,52 @@ func Open(path string, buckets ...[]byte) (*bbolt.DB, error) {
   return db, nil
 }
 
+func GetTx(tx *bbolt.Tx, bucket, key []byte) []byte {
+  if tx == nil || len(bucket) == 0 || len(key) == 0 {
+    return nil
+  }
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return nil
+  }
+  val := b.Get(key)
+  if val == nil {
+    return nil
+  }
+  ret := make([]byte, len(val))
+  copy(ret, val)
+  return ret
+}
+
 func Get(db *bbolt.DB, bucket, key []byte) []byte {
   if db == nil || len(bucket) == 0 || len(key) == 0 {
     return nil
   }
   var ret []byte
-  e := db.View(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
-    }
-    val := b.Get(key)
-    if val == nil {
-      return ErrKeyNotFound
-    }
-    ret = make([]byte, len(val))
-    copy(ret, val)
+  db.View(func(tx *bbolt.Tx) error {
+    ret = GetTx(tx, bucket, key)
     return nil
   })
-  if e != nil {
-    return nil
-  }
   return ret
 }
 
+func PutTx(tx *bbolt.Tx, bucket, key, value []byte) error {
+  if tx == nil || len(bucket) == 0 || len(key) == 0 {
+    return base.ErrInvalidArgument
+  }
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return ErrBucketNotFound
+  }
+  return bucketPut(b, key, value)
+}
+
 func Put(db *bbolt.DB, bucket, key, value []byte) error {
-  if db == nil || len(bucket) == 0 || len(key) == 0 || len(value) == 0 {
+  if db == nil {
     return base.ErrInvalidArgument
   }
   return db.Update(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
-    }
-    return b.Put(key, value)
+    return PutTx(tx, bucket, key, value)
   })
 }
 
@@ -119,32 +183,39 @@ func PutWithValue(db *bbolt.DB, bucket, key []byte, fun func([]byte) ([]byte, er
   })
 }
 
-func PutWithValuePrefix(db *bbolt.DB, bucket, prefix []byte, fun func([]byte) ([]byte, error)) error {
-  if db == nil || len(bucket) == 0 || len(prefix) == 0 || fun == nil {
+func PutWithValuePrefixTx(tx *bbolt.Tx, bucket, prefix []byte, fun func([]byte) ([]byte, error)) error {
+  if tx == nil || len(bucket) == 0 || len(prefix) == 0 || fun == nil {
     return base.ErrInvalidArgument
   }
-  return db.Update(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return ErrBucketNotFound
+  }
+  c := b.Cursor()
+  for k, v := c.Seek(prefix); k != nil; k, v = c.Next() {
+    if !bytes.HasPrefix(k, prefix) {
+      break
     }
-    c := b.Cursor()
-    for k, v := c.Seek(prefix); k != nil; k, v = c.Next() {
-      if !bytes.HasPrefix(k, prefix) {
-        break
-      }
-      newVal, e := fun(v)
+    newVal, e := fun(v)
+    if e != nil {
+      return e
+    }
+    if newVal != nil {
+      e = b.Put(k, newVal)
       if e != nil {
         return e
       }
-      if newVal != nil {
-        e = b.Put(k, newVal)
-        if e != nil {
-          return e
-        }
-      }
     }
-    return nil
+  }
+  return nil
+}
+
+func PutWithValuePrefix(db *bbolt.DB, bucket, prefix []byte, fun func([]byte) ([]byte, error)) error {
+  if db == nil {
+    return base.ErrInvalidArgument
+  }
+  return db.Update(func(tx *bbolt.Tx) error {
+    return PutWithValuePrefixTx(tx, bucket, prefix, fun)
   })
 }
 
@@ -188,44 +259,43 @@ func PutWithDB(db *bbolt.DB, fun func(*bbolt.Tx) error) error {
   return db.Update(fun)
 }
 
+func EachKVTx(tx *bbolt.Tx, bucket []byte, fun func([]byte, []byte) error) error {
+  if tx == nil || len(bucket) == 0 || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return ErrBucketNotFound
+  }
+  return bucketEach(b, fun)
+}
+
 func EachKV(db *bbolt.DB, bucket []byte, fun func([]byte, []byte) error) error {
-  if db == nil || len(bucket) == 0 || fun == nil {
+  if db == nil {
     return base.ErrInvalidArgument
   }
   return db.View(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
-    }
-    c := b.Cursor()
-    for k, v := c.First(); k != nil; k, v = c.Next() {
-      if e := fun(k, v); e != nil {
-        return e
-      }
-    }
-    return nil
+    return EachKVTx(tx, bucket, fun)
   })
 }
 
+func EachKVPrefixTx(tx *bbolt.Tx, bucket, prefix []byte, fun func([]byte, []byte) error) error {
+  if tx == nil || len(bucket) == 0 || len(prefix) == 0 || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return ErrBucketNotFound
+  }
+  return bucketEachPrefix(b, prefix, fun)
+}
+
 func EachKVPrefix(db *bbolt.DB, bucket, prefix []byte, fun func([]byte, []byte) error) error {
-  if db == nil || len(bucket) == 0 || len(prefix) == 0 || fun == nil {
+  if db == nil {
     return base.ErrInvalidArgument
   }
   return db.View(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
-    }
-    c := b.Cursor()
-    for k, v := c.Seek(prefix); k != nil; k, v = c.Next() {
-      if !bytes.HasPrefix(k, prefix) {
-        break
-      }
-      if e := fun(k, v); e != nil {
-        return e
-      }
-    }
-    return nil
+    return EachKVPrefixTx(tx, bucket, prefix, fun)
   })
 }
 
@@ -242,18 +312,26 @@ func EachBucket(db *bbolt.DB, bucket []byte, fun func(*bbolt.Bucket) error) erro
   })
 }
 
+func CountKVTx(tx *bbolt.Tx, bucket []byte) (int, error) {
+  if tx == nil || len(bucket) == 0 {
+    return 0, base.ErrInvalidArgument
+  }
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return 0, ErrBucketNotFound
+  }
+  return bucketCount(b), nil
+}
+
 func CountKV(db *bbolt.DB, bucket []byte) (int, error) {
-  if db == nil || len(bucket) == 0 {
+  if db == nil {
     return 0, base.ErrInvalidArgument
   }
-  ret := 0
+  var ret int
   e := db.View(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
-    }
-    ret = b.Stats().KeyN
-    return nil
+    var e error
+    ret, e = CountKVTx(tx, bucket)
+    return e
   })
   if e != nil {
     return 0, e
@@ -261,24 +339,26 @@ func CountKV(db *bbolt.DB, bucket []byte) (int, error) {
   return ret, nil
 }
 
+func CountKVPrefixTx(tx *bbolt.Tx, bucket, prefix []byte) (int, error) {
+  if tx == nil || len(bucket) == 0 || len(prefix) == 0 {
+    return 0, base.ErrInvalidArgument
+  }
+  b := tx.Bucket(bucket)
+  if b == nil {
+    return 0, ErrBucketNotFound
+  }
+  return bucketCountPrefix(b, prefix), nil
+}
+
 func CountKVPrefix(db *bbolt.DB, bucket, prefix []byte) (int, error) {
-  if db == nil || len(bucket) == 0 || len(prefix) == 0 {
+  if db == nil {
     return 0, base.ErrInvalidArgument
   }
-  ret := 0
+  var ret int
   e := db.View(func(tx *bbolt.Tx) error {
-    b := tx.Bucket(bucket)
-    if b == nil {
-      return ErrBucketNotFound
-    }
-    c := b.Cursor()
-    for k, _ := c.Seek(prefix); k != nil; k, _ = c.Next() {
-      if !bytes.HasPrefix(k, prefix) {
-        break
-      }
-      ret++
-    }
-    return nil
+    var e error
+    ret, e = CountKVPrefixTx(tx, bucket, prefix)
+    return e
   })
   if e != nil {
     return 0, e
@@ -0,0 +1,40 @@
+package bolt
+
+import (
+  "os"
+  "testing"
+)
+
+func TestPutZeroLengthValue(t *testing.T) {
+  f, e := os.CreateTemp("", "bolt_test_*.db")
+  if e != nil {
+    t.Fatal(e)
+  }
+  f.Close()
+  defer os.Remove(f.Name())
+
+  db, e := Open(f.Name(), []byte("b"))
+  if e != nil {
+    t.Fatal(e)
+  }
+  defer db.Close()
+
+  if e := Put(db, []byte("b"), []byte("k"), []byte{}); e != nil {
+    t.Fatal(e)
+  }
+  val := Get(db, []byte("b"), []byte("k"))
+  if val == nil {
+    t.Fatal("expected an empty, non-nil value for a present key")
+  }
+  if len(val) != 0 {
+    t.Fatalf("expected zero-length value, got %q", val)
+  }
+
+  if e := Put(db, []byte("b"), []byte("k2"), nil); e != nil {
+    t.Fatal(e)
+  }
+  val2 := Get(db, []byte("b"), []byte("k2"))
+  if val2 == nil {
+    t.Fatal("nil value should normalize to an empty, non-nil retrievable value")
+  }
+}
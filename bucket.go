@@ -0,0 +1,100 @@
+package bolt
+
+import (
+  "github.com/kwf2030/commons/base"
+  "go.etcd.io/bbolt"
+)
+
+type Bucket struct {
+  db   *bbolt.DB
+  name []byte
+}
+
+func OpenBucket(db *bbolt.DB, name []byte) (*Bucket, error) {
+  if db == nil || len(name) == 0 {
+    return nil, base.ErrInvalidArgument
+  }
+  e := db.Update(func(tx *bbolt.Tx) error {
+    _, e := tx.CreateBucketIfNotExists(name)
+    return e
+  })
+  if e != nil {
+    return nil, e
+  }
+  return &Bucket{db: db, name: name}, nil
+}
+
+func (b *Bucket) Get(key []byte) []byte {
+  if b == nil {
+    return nil
+  }
+  return Get(b.db, b.name, key)
+}
+
+func (b *Bucket) Put(key, value []byte) error {
+  if b == nil {
+    return base.ErrInvalidArgument
+  }
+  return Put(b.db, b.name, key, value)
+}
+
+func (b *Bucket) Delete(key []byte) error {
+  if b == nil || len(key) == 0 {
+    return base.ErrInvalidArgument
+  }
+  return b.db.Update(func(tx *bbolt.Tx) error {
+    bkt := tx.Bucket(b.name)
+    if bkt == nil {
+      return ErrBucketNotFound
+    }
+    return bkt.Delete(key)
+  })
+}
+
+func (b *Bucket) Each(fun func(k, v []byte) error) error {
+  if b == nil {
+    return base.ErrInvalidArgument
+  }
+  return EachKV(b.db, b.name, fun)
+}
+
+func (b *Bucket) EachPrefix(prefix []byte, fun func(k, v []byte) error) error {
+  if b == nil {
+    return base.ErrInvalidArgument
+  }
+  return EachKVPrefix(b.db, b.name, prefix, fun)
+}
+
+func (b *Bucket) Count() (int, error) {
+  if b == nil {
+    return 0, base.ErrInvalidArgument
+  }
+  return CountKV(b.db, b.name)
+}
+
+func (b *Bucket) CountPrefix(prefix []byte) (int, error) {
+  if b == nil {
+    return 0, base.ErrInvalidArgument
+  }
+  return CountKVPrefix(b.db, b.name, prefix)
+}
+
+func (b *Bucket) Update(fun func(*bbolt.Bucket) error) error {
+  if b == nil {
+    return base.ErrInvalidArgument
+  }
+  return PutWithBucket(b.db, b.name, fun)
+}
+
+func (b *Bucket) View(fun func(*bbolt.Bucket) error) error {
+  if b == nil {
+    return base.ErrInvalidArgument
+  }
+  return GetWithBucket(b.db, b.name, fun)
+}
+
+// Close is a no-op; it exists so callers can follow the usual
+// open/close bucket-lifecycle pattern.
+func (b *Bucket) Close() error {
+  return nil
+}
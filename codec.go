@@ -0,0 +1,59 @@
+package bolt
+
+import (
+  "bytes"
+  "encoding/gob"
+  "encoding/json"
+
+  "github.com/kwf2030/commons/base"
+  "google.golang.org/protobuf/proto"
+)
+
+type Codec interface {
+  Marshal(v interface{}) ([]byte, error)
+  Unmarshal(data []byte, v interface{}) error
+}
+
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+  buf := &bytes.Buffer{}
+  if e := gob.NewEncoder(buf).Encode(v); e != nil {
+    return nil, e
+  }
+  return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+  return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+  return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+  return json.Unmarshal(data, v)
+}
+
+// ProtoCodec encodes values with google.golang.org/protobuf. The v passed
+// to Marshal and Unmarshal must implement proto.Message.
+type ProtoCodec struct{}
+
+func (ProtoCodec) Marshal(v interface{}) ([]byte, error) {
+  m, ok := v.(proto.Message)
+  if !ok {
+    return nil, base.ErrInvalidArgument
+  }
+  return proto.Marshal(m)
+}
+
+func (ProtoCodec) Unmarshal(data []byte, v interface{}) error {
+  m, ok := v.(proto.Message)
+  if !ok {
+    return base.ErrInvalidArgument
+  }
+  return proto.Unmarshal(data, m)
+}
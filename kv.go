@@ -0,0 +1,222 @@
+package bolt
+
+import (
+  "bytes"
+
+  "github.com/kwf2030/commons/base"
+  "go.etcd.io/bbolt"
+)
+
+type KV interface {
+  View(fun func(ReadTx) error) error
+  Update(fun func(ReadWriteTx) error) error
+}
+
+type ReadTx interface {
+  Bucket(name []byte) KVBucket
+}
+
+type ReadWriteTx interface {
+  ReadTx
+  CreateBucketIfNotExists(name []byte) (KVBucket, error)
+}
+
+type BucketStats struct {
+  KeyN int
+}
+
+type KVBucket interface {
+  Get(key []byte) []byte
+  Put(key, value []byte) error
+  Delete(key []byte) error
+  Cursor() Cursor
+  ForEach(fun func(k, v []byte) error) error
+  Stats() BucketStats
+}
+
+type Cursor interface {
+  First() (key, value []byte)
+  Next() (key, value []byte)
+  Seek(prefix []byte) (key, value []byte)
+}
+
+func KVGet(kv KV, bucket, key []byte) []byte {
+  if kv == nil || len(bucket) == 0 || len(key) == 0 {
+    return nil
+  }
+  var ret []byte
+  kv.View(func(tx ReadTx) error {
+    b := tx.Bucket(bucket)
+    if b == nil {
+      return nil
+    }
+    val := b.Get(key)
+    if val == nil {
+      return nil
+    }
+    ret = make([]byte, len(val))
+    copy(ret, val)
+    return nil
+  })
+  return ret
+}
+
+func KVPut(kv KV, bucket, key, value []byte) error {
+  if kv == nil || len(bucket) == 0 || len(key) == 0 {
+    return base.ErrInvalidArgument
+  }
+  return kv.Update(func(tx ReadWriteTx) error {
+    b := tx.Bucket(bucket)
+    if b == nil {
+      return ErrBucketNotFound
+    }
+    if value == nil {
+      value = []byte{}
+    }
+    return b.Put(key, value)
+  })
+}
+
+func KVEachPrefix(kv KV, bucket, prefix []byte, fun func(k, v []byte) error) error {
+  if kv == nil || len(bucket) == 0 || len(prefix) == 0 || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  return kv.View(func(tx ReadTx) error {
+    b := tx.Bucket(bucket)
+    if b == nil {
+      return ErrBucketNotFound
+    }
+    c := b.Cursor()
+    for k, v := c.Seek(prefix); k != nil; k, v = c.Next() {
+      if !bytes.HasPrefix(k, prefix) {
+        break
+      }
+      if e := fun(k, v); e != nil {
+        return e
+      }
+    }
+    return nil
+  })
+}
+
+func KVCount(kv KV, bucket []byte) (int, error) {
+  if kv == nil || len(bucket) == 0 {
+    return 0, base.ErrInvalidArgument
+  }
+  var ret int
+  e := kv.View(func(tx ReadTx) error {
+    b := tx.Bucket(bucket)
+    if b == nil {
+      return ErrBucketNotFound
+    }
+    ret = b.Stats().KeyN
+    return nil
+  })
+  if e != nil {
+    return 0, e
+  }
+  return ret, nil
+}
+
+type BoltKV struct {
+  db *bbolt.DB
+}
+
+func NewBoltKV(db *bbolt.DB) (*BoltKV, error) {
+  if db == nil {
+    return nil, base.ErrInvalidArgument
+  }
+  return &BoltKV{db: db}, nil
+}
+
+func (kv *BoltKV) View(fun func(ReadTx) error) error {
+  return kv.db.View(func(tx *bbolt.Tx) error {
+    return fun(boltTx{tx})
+  })
+}
+
+func (kv *BoltKV) Update(fun func(ReadWriteTx) error) error {
+  return kv.db.Update(func(tx *bbolt.Tx) error {
+    return fun(boltTx{tx})
+  })
+}
+
+func (kv *BoltKV) Get(bucket, key []byte) []byte {
+  return KVGet(kv, bucket, key)
+}
+
+func (kv *BoltKV) Put(bucket, key, value []byte) error {
+  return KVPut(kv, bucket, key, value)
+}
+
+func (kv *BoltKV) EachKVPrefix(bucket, prefix []byte, fun func(k, v []byte) error) error {
+  return KVEachPrefix(kv, bucket, prefix, fun)
+}
+
+func (kv *BoltKV) CountKV(bucket []byte) (int, error) {
+  return KVCount(kv, bucket)
+}
+
+type boltTx struct {
+  tx *bbolt.Tx
+}
+
+func (t boltTx) Bucket(name []byte) KVBucket {
+  b := t.tx.Bucket(name)
+  if b == nil {
+    return nil
+  }
+  return boltBucket{b}
+}
+
+func (t boltTx) CreateBucketIfNotExists(name []byte) (KVBucket, error) {
+  b, e := t.tx.CreateBucketIfNotExists(name)
+  if e != nil {
+    return nil, e
+  }
+  return boltBucket{b}, nil
+}
+
+type boltBucket struct {
+  b *bbolt.Bucket
+}
+
+func (b boltBucket) Get(key []byte) []byte {
+  return b.b.Get(key)
+}
+
+func (b boltBucket) Put(key, value []byte) error {
+  return b.b.Put(key, value)
+}
+
+func (b boltBucket) Delete(key []byte) error {
+  return b.b.Delete(key)
+}
+
+func (b boltBucket) Cursor() Cursor {
+  return boltCursor{b.b.Cursor()}
+}
+
+func (b boltBucket) ForEach(fun func(k, v []byte) error) error {
+  return b.b.ForEach(fun)
+}
+
+func (b boltBucket) Stats() BucketStats {
+  return BucketStats{KeyN: b.b.Stats().KeyN}
+}
+
+type boltCursor struct {
+  c *bbolt.Cursor
+}
+
+func (c boltCursor) First() (key, value []byte) {
+  return c.c.First()
+}
+
+func (c boltCursor) Next() (key, value []byte) {
+  return c.c.Next()
+}
+
+func (c boltCursor) Seek(prefix []byte) (key, value []byte) {
+  return c.c.Seek(prefix)
+}
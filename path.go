@@ -0,0 +1,166 @@
+package bolt
+
+import (
+  "os"
+
+  "github.com/kwf2030/commons/base"
+  "go.etcd.io/bbolt"
+)
+
+// Path identifies a (possibly nested) bucket by the chain of bucket
+// names leading to it, e.g. Path{[]byte("a"), []byte("b")} is bucket
+// "b" nested inside bucket "a".
+type Path [][]byte
+
+func resolveBucket(tx *bbolt.Tx, path Path) (*bbolt.Bucket, error) {
+  if len(path) == 0 {
+    return nil, base.ErrInvalidArgument
+  }
+  b := tx.Bucket(path[0])
+  if b == nil {
+    return nil, ErrBucketNotFound
+  }
+  for _, name := range path[1:] {
+    b = b.Bucket(name)
+    if b == nil {
+      return nil, ErrBucketNotFound
+    }
+  }
+  return b, nil
+}
+
+func createBucketPath(tx *bbolt.Tx, path Path) (*bbolt.Bucket, error) {
+  if len(path) == 0 {
+    return nil, base.ErrInvalidArgument
+  }
+  b, e := tx.CreateBucketIfNotExists(path[0])
+  if e != nil {
+    return nil, e
+  }
+  for _, name := range path[1:] {
+    b, e = b.CreateBucketIfNotExists(name)
+    if e != nil {
+      return nil, e
+    }
+  }
+  return b, nil
+}
+
+func OpenPath(path string, paths ...Path) (*bbolt.DB, error) {
+  if path == "" {
+    return nil, base.ErrInvalidArgument
+  }
+  db, e := bbolt.Open(path, os.ModePerm, nil)
+  if e != nil {
+    return nil, e
+  }
+  if len(paths) > 0 {
+    e = db.Update(func(tx *bbolt.Tx) error {
+      for _, p := range paths {
+        if len(p) > 0 {
+          _, e := createBucketPath(tx, p)
+          if e != nil {
+            return e
+          }
+        }
+      }
+      return nil
+    })
+    if e != nil {
+      return nil, e
+    }
+  }
+  return db, nil
+}
+
+func GetPath(db *bbolt.DB, path Path, key []byte) []byte {
+  if db == nil || len(path) == 0 || len(key) == 0 {
+    return nil
+  }
+  var ret []byte
+  db.View(func(tx *bbolt.Tx) error {
+    b, e := resolveBucket(tx, path)
+    if e != nil {
+      return e
+    }
+    val := b.Get(key)
+    if val == nil {
+      return ErrKeyNotFound
+    }
+    ret = make([]byte, len(val))
+    copy(ret, val)
+    return nil
+  })
+  return ret
+}
+
+func PutPath(db *bbolt.DB, path Path, key, value []byte) error {
+  if db == nil || len(path) == 0 || len(key) == 0 {
+    return base.ErrInvalidArgument
+  }
+  return db.Update(func(tx *bbolt.Tx) error {
+    b, e := resolveBucket(tx, path)
+    if e != nil {
+      return e
+    }
+    return bucketPut(b, key, value)
+  })
+}
+
+func EachKVPath(db *bbolt.DB, path Path, fun func([]byte, []byte) error) error {
+  if db == nil || len(path) == 0 || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  return db.View(func(tx *bbolt.Tx) error {
+    b, e := resolveBucket(tx, path)
+    if e != nil {
+      return e
+    }
+    return bucketEach(b, fun)
+  })
+}
+
+func EachKVPrefixPath(db *bbolt.DB, path Path, prefix []byte, fun func([]byte, []byte) error) error {
+  if db == nil || len(path) == 0 || len(prefix) == 0 || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  return db.View(func(tx *bbolt.Tx) error {
+    b, e := resolveBucket(tx, path)
+    if e != nil {
+      return e
+    }
+    return bucketEachPrefix(b, prefix, fun)
+  })
+}
+
+func EachBucketPath(db *bbolt.DB, path Path, fun func(*bbolt.Bucket) error) error {
+  if db == nil || len(path) == 0 || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  return db.View(func(tx *bbolt.Tx) error {
+    b, e := resolveBucket(tx, path)
+    if e != nil {
+      return e
+    }
+    return fun(b)
+  })
+}
+
+func CountKVPath(db *bbolt.DB, path Path) (int, error) {
+  if db == nil || len(path) == 0 {
+    return 0, base.ErrInvalidArgument
+  }
+  var ret int
+  e := db.View(func(tx *bbolt.Tx) error {
+    b, e := resolveBucket(tx, path)
+    if e != nil {
+      return e
+    }
+    ret = bucketCount(b)
+    return nil
+  })
+  if e != nil {
+    return 0, e
+  }
+  return ret, nil
+}
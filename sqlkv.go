@@ -0,0 +1,196 @@
+package bolt
+
+import (
+  "database/sql"
+
+  "github.com/kwf2030/commons/base"
+)
+
+// SQLDialect selects the upsert syntax sqlBucket.Put uses, since there's
+// no database/sql-portable way to do an atomic upsert.
+type SQLDialect int
+
+const (
+  DialectSQLite SQLDialect = iota
+  DialectPostgres
+  DialectMySQL
+)
+
+// SQLKV expects a table with the schema:
+//
+//   CREATE TABLE bolt_kv (
+//     bucket BLOB NOT NULL,
+//     key    BLOB NOT NULL,
+//     value  BLOB NOT NULL,
+//     PRIMARY KEY(bucket, key)
+//   )
+type SQLKV struct {
+  db      *sql.DB
+  table   string
+  dialect SQLDialect
+}
+
+func NewSQLKV(db *sql.DB, table string, dialect SQLDialect) (*SQLKV, error) {
+  if db == nil || table == "" {
+    return nil, base.ErrInvalidArgument
+  }
+  return &SQLKV{db: db, table: table, dialect: dialect}, nil
+}
+
+func (kv *SQLKV) Get(bucket, key []byte) []byte {
+  return KVGet(kv, bucket, key)
+}
+
+func (kv *SQLKV) Put(bucket, key, value []byte) error {
+  return KVPut(kv, bucket, key, value)
+}
+
+func (kv *SQLKV) EachKVPrefix(bucket, prefix []byte, fun func(k, v []byte) error) error {
+  return KVEachPrefix(kv, bucket, prefix, fun)
+}
+
+func (kv *SQLKV) CountKV(bucket []byte) (int, error) {
+  return KVCount(kv, bucket)
+}
+
+func (kv *SQLKV) View(fun func(ReadTx) error) error {
+  tx, e := kv.db.Begin()
+  if e != nil {
+    return e
+  }
+  defer tx.Rollback()
+  return fun(sqlTx{tx: tx, table: kv.table, dialect: kv.dialect})
+}
+
+func (kv *SQLKV) Update(fun func(ReadWriteTx) error) error {
+  tx, e := kv.db.Begin()
+  if e != nil {
+    return e
+  }
+  e = fun(sqlTx{tx: tx, table: kv.table, dialect: kv.dialect})
+  if e != nil {
+    tx.Rollback()
+    return e
+  }
+  return tx.Commit()
+}
+
+type sqlTx struct {
+  tx      *sql.Tx
+  table   string
+  dialect SQLDialect
+}
+
+func (t sqlTx) Bucket(name []byte) KVBucket {
+  return sqlBucket{tx: t.tx, table: t.table, dialect: t.dialect, bucket: name}
+}
+
+func (t sqlTx) CreateBucketIfNotExists(name []byte) (KVBucket, error) {
+  return sqlBucket{tx: t.tx, table: t.table, dialect: t.dialect, bucket: name}, nil
+}
+
+type sqlBucket struct {
+  tx      *sql.Tx
+  table   string
+  dialect SQLDialect
+  bucket  []byte
+}
+
+func (b sqlBucket) Get(key []byte) []byte {
+  var value []byte
+  row := b.tx.QueryRow("SELECT value FROM "+b.table+" WHERE bucket = ? AND key = ?", b.bucket, key)
+  if e := row.Scan(&value); e != nil {
+    return nil
+  }
+  return value
+}
+
+// Put upserts atomically, since UPDATE-then-INSERT is check-then-act and
+// races under concurrent writers. The upsert clause differs by engine,
+// hence SQLDialect.
+func (b sqlBucket) Put(key, value []byte) error {
+  var q string
+  switch b.dialect {
+  case DialectMySQL:
+    q = "INSERT INTO " + b.table + " (bucket, key, value) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value)"
+  default:
+    q = "INSERT INTO " + b.table + " (bucket, key, value) VALUES (?, ?, ?) ON CONFLICT(bucket, key) DO UPDATE SET value = excluded.value"
+  }
+  _, e := b.tx.Exec(q, b.bucket, key, value)
+  return e
+}
+
+func (b sqlBucket) Delete(key []byte) error {
+  _, e := b.tx.Exec("DELETE FROM "+b.table+" WHERE bucket = ? AND key = ?", b.bucket, key)
+  return e
+}
+
+func (b sqlBucket) Cursor() Cursor {
+  rows, e := b.tx.Query("SELECT key, value FROM "+b.table+" WHERE bucket = ? ORDER BY key", b.bucket)
+  if e != nil {
+    return &sqlCursor{}
+  }
+  var keys, values [][]byte
+  for rows.Next() {
+    var k, v []byte
+    if rows.Scan(&k, &v) == nil {
+      keys = append(keys, k)
+      values = append(values, v)
+    }
+  }
+  rows.Close()
+  return &sqlCursor{keys: keys, values: values, pos: -1}
+}
+
+func (b sqlBucket) ForEach(fun func(k, v []byte) error) error {
+  c := b.Cursor()
+  for k, v := c.First(); k != nil; k, v = c.Next() {
+    if e := fun(k, v); e != nil {
+      return e
+    }
+  }
+  return nil
+}
+
+func (b sqlBucket) Stats() BucketStats {
+  var n int
+  row := b.tx.QueryRow("SELECT COUNT(*) FROM "+b.table+" WHERE bucket = ?", b.bucket)
+  row.Scan(&n)
+  return BucketStats{KeyN: n}
+}
+
+// sqlCursor is an in-memory snapshot of a bucket's contents in key
+// order, since database/sql has no native cursor API to seek by byte
+// order across drivers.
+type sqlCursor struct {
+  keys, values [][]byte
+  pos          int
+}
+
+func (c *sqlCursor) First() (key, value []byte) {
+  c.pos = 0
+  return c.at(c.pos)
+}
+
+func (c *sqlCursor) Next() (key, value []byte) {
+  c.pos++
+  return c.at(c.pos)
+}
+
+func (c *sqlCursor) Seek(prefix []byte) (key, value []byte) {
+  for i, k := range c.keys {
+    if len(k) >= len(prefix) && string(k[:len(prefix)]) >= string(prefix) {
+      c.pos = i
+      return c.at(c.pos)
+    }
+  }
+  c.pos = len(c.keys)
+  return nil, nil
+}
+
+func (c *sqlCursor) at(i int) (key, value []byte) {
+  if i < 0 || i >= len(c.keys) {
+    return nil, nil
+  }
+  return c.keys[i], c.values[i]
+}
@@ -0,0 +1,59 @@
+package bolt
+
+import (
+  "database/sql"
+  "sync"
+  "testing"
+
+  _ "modernc.org/sqlite"
+)
+
+func TestSQLBucketPutConcurrent(t *testing.T) {
+  db, e := sql.Open("sqlite", "file::memory:?cache=shared")
+  if e != nil {
+    t.Fatal(e)
+  }
+  defer db.Close()
+  db.SetMaxOpenConns(1)
+
+  _, e = db.Exec(`CREATE TABLE bolt_kv (
+    bucket BLOB NOT NULL,
+    key    BLOB NOT NULL,
+    value  BLOB NOT NULL,
+    PRIMARY KEY(bucket, key)
+  )`)
+  if e != nil {
+    t.Fatal(e)
+  }
+
+  kv, e := NewSQLKV(db, "bolt_kv", DialectSQLite)
+  if e != nil {
+    t.Fatal(e)
+  }
+
+  const n = 20
+  var wg sync.WaitGroup
+  errs := make(chan error, n)
+  for i := 0; i < n; i++ {
+    wg.Add(1)
+    go func(i int) {
+      defer wg.Done()
+      errs <- KVPut(kv, []byte("b"), []byte("k"), []byte{byte(i)})
+    }(i)
+  }
+  wg.Wait()
+  close(errs)
+  for e := range errs {
+    if e != nil {
+      t.Fatal(e)
+    }
+  }
+
+  count, e := KVCount(kv, []byte("b"))
+  if e != nil {
+    t.Fatal(e)
+  }
+  if count != 1 {
+    t.Fatalf("expected one row for key \"k\" after concurrent puts, got %d", count)
+  }
+}
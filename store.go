@@ -0,0 +1,64 @@
+package bolt
+
+import (
+  "github.com/kwf2030/commons/base"
+  "go.etcd.io/bbolt"
+)
+
+type Store struct {
+  db    *bbolt.DB
+  codec Codec
+}
+
+func NewStore(db *bbolt.DB, codec Codec) (*Store, error) {
+  if db == nil || codec == nil {
+    return nil, base.ErrInvalidArgument
+  }
+  return &Store{db: db, codec: codec}, nil
+}
+
+func (s *Store) GetObject(bucket, key []byte, out interface{}) error {
+  if s == nil || out == nil {
+    return base.ErrInvalidArgument
+  }
+  return GetWithValue(s.db, bucket, key, func(val []byte) error {
+    return s.codec.Unmarshal(val, out)
+  })
+}
+
+func (s *Store) PutObject(bucket, key []byte, in interface{}) error {
+  if s == nil || in == nil {
+    return base.ErrInvalidArgument
+  }
+  data, e := s.codec.Marshal(in)
+  if e != nil {
+    return e
+  }
+  return Put(s.db, bucket, key, data)
+}
+
+func (s *Store) EachObject(bucket []byte, newElem func() interface{}, fun func(k []byte, v interface{}) error) error {
+  if s == nil || newElem == nil || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  return EachKV(s.db, bucket, func(k, v []byte) error {
+    elem := newElem()
+    if e := s.codec.Unmarshal(v, elem); e != nil {
+      return e
+    }
+    return fun(k, elem)
+  })
+}
+
+func (s *Store) EachObjectPrefix(bucket, prefix []byte, newElem func() interface{}, fun func(k []byte, v interface{}) error) error {
+  if s == nil || newElem == nil || fun == nil {
+    return base.ErrInvalidArgument
+  }
+  return EachKVPrefix(s.db, bucket, prefix, func(k, v []byte) error {
+    elem := newElem()
+    if e := s.codec.Unmarshal(v, elem); e != nil {
+      return e
+    }
+    return fun(k, elem)
+  })
+}